@@ -0,0 +1,133 @@
+package httption
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// Authenticator mutates an in-flight BaseAction to attach credentials to
+// the outgoing request. It runs after headers and body are set in
+// BaseAction.Setup, so implementations operate directly on ba.headers,
+// ba.url and ba.requestBodyBuffer.
+type Authenticator interface {
+	Authenticate(ba *BaseAction) error
+}
+
+type headerAuth struct {
+	name  string
+	value string
+}
+
+// NewHeaderAuth returns an Authenticator that sets a static header,
+// e.g. an API key sent as `X-Api-Key: <value>`.
+func NewHeaderAuth(name, value string) Authenticator {
+	return &headerAuth{name: name, value: value}
+}
+
+func (a *headerAuth) Authenticate(ba *BaseAction) error {
+	if ba.headers == nil {
+		ba.headers = make(map[string]string)
+	}
+	ba.headers[a.name] = a.value
+
+	return nil
+}
+
+// NewBearer returns an Authenticator that sets the Authorization header
+// to `Bearer <token>`.
+func NewBearer(token string) Authenticator {
+	return NewHeaderAuth("Authorization", "Bearer "+token)
+}
+
+type basicAuth struct {
+	user string
+	pass string
+}
+
+// NewBasicAuth returns an Authenticator that sets the Authorization
+// header using HTTP basic auth.
+func NewBasicAuth(user, pass string) Authenticator {
+	return &basicAuth{user: user, pass: pass}
+}
+
+func (a *basicAuth) Authenticate(ba *BaseAction) error {
+	if ba.headers == nil {
+		ba.headers = make(map[string]string)
+	}
+	ba.headers["Authorization"] = basicAuthHeader(a.user, a.pass)
+
+	return nil
+}
+
+type queryAuth struct {
+	param string
+	value string
+}
+
+// NewQueryAuth returns an Authenticator that adds a query-string
+// parameter to the request URL, e.g. an API key sent as `?api_key=...`.
+func NewQueryAuth(param, value string) Authenticator {
+	return &queryAuth{param: param, value: value}
+}
+
+func (a *queryAuth) Authenticate(ba *BaseAction) error {
+	u, err := url.Parse(ba.url)
+	if err != nil {
+		return err
+	}
+
+	q := u.Query()
+	q.Set(a.param, a.value)
+	u.RawQuery = q.Encode()
+
+	ba.url = u.String()
+
+	return nil
+}
+
+type jsonBodyAuth struct {
+	field string
+	value string
+}
+
+// NewJSONBodyAuth returns an Authenticator that injects a field into a
+// JSON request body, e.g. an API key sent as part of the payload.
+// It only applies when the request's Content-Type is application/json.
+func NewJSONBodyAuth(field, value string) Authenticator {
+	return &jsonBodyAuth{field: field, value: value}
+}
+
+func (a *jsonBodyAuth) Authenticate(ba *BaseAction) error {
+	v, ok := headerLookup(ba.headers, "Content-Type")
+	if !ok || !strings.HasPrefix(strings.ToLower(v), "application/json") {
+		return nil
+	}
+
+	body := map[string]any{}
+	if len(ba.requestBodyBuffer) > 0 {
+		if err := json.Unmarshal(ba.requestBodyBuffer, &body); err != nil {
+			return err
+		}
+	}
+
+	body[a.field] = a.value
+
+	encoded, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+
+	ba.requestBodyBuffer = bytes.TrimRight(encoded, "\n")
+
+	return nil
+}
+
+func basicAuthHeader(user, pass string) string {
+	req := &http.Request{Header: make(http.Header)}
+	req.SetBasicAuth(user, pass)
+
+	return req.Header.Get("Authorization")
+}
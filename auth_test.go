@@ -0,0 +1,175 @@
+package httption
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestHeaderAuth(t *testing.T) {
+	ba := NewBaseAction(&http.Client{}, "GET", "http://example.com")
+
+	if err := NewHeaderAuth("X-Api-Key", "secret").Authenticate(ba); err != nil {
+		t.Fatalf("Authenticate: %v", err)
+	}
+	if ba.headers["X-Api-Key"] != "secret" {
+		t.Errorf("headers[X-Api-Key] = %q, want secret", ba.headers["X-Api-Key"])
+	}
+}
+
+func TestHeaderAuth_InitializesNilHeaders(t *testing.T) {
+	ba := NewBaseAction(&http.Client{}, "GET", "http://example.com")
+	ba.headers = nil
+
+	if err := NewHeaderAuth("X-Api-Key", "secret").Authenticate(ba); err != nil {
+		t.Fatalf("Authenticate: %v", err)
+	}
+	if ba.headers["X-Api-Key"] != "secret" {
+		t.Error("expected Authenticate to initialize a nil headers map")
+	}
+}
+
+func TestBearer(t *testing.T) {
+	ba := NewBaseAction(&http.Client{}, "GET", "http://example.com")
+
+	if err := NewBearer("token-123").Authenticate(ba); err != nil {
+		t.Fatalf("Authenticate: %v", err)
+	}
+	if ba.headers["Authorization"] != "Bearer token-123" {
+		t.Errorf("Authorization = %q, want \"Bearer token-123\"", ba.headers["Authorization"])
+	}
+}
+
+func TestBasicAuth(t *testing.T) {
+	ba := NewBaseAction(&http.Client{}, "GET", "http://example.com")
+
+	if err := NewBasicAuth("alice", "hunter2").Authenticate(ba); err != nil {
+		t.Fatalf("Authenticate: %v", err)
+	}
+
+	req, _ := http.NewRequest("GET", "http://example.com", nil)
+	req.Header.Set("Authorization", ba.headers["Authorization"])
+	user, pass, ok := req.BasicAuth()
+	if !ok || user != "alice" || pass != "hunter2" {
+		t.Errorf("BasicAuth() = (%q, %q, %v), want (alice, hunter2, true)", user, pass, ok)
+	}
+}
+
+func TestQueryAuth(t *testing.T) {
+	ba := NewBaseAction(&http.Client{}, "GET", "http://example.com/things?existing=1")
+
+	if err := NewQueryAuth("api_key", "secret").Authenticate(ba); err != nil {
+		t.Fatalf("Authenticate: %v", err)
+	}
+
+	u, err := url.Parse(ba.url)
+	if err != nil {
+		t.Fatalf("url.Parse: %v", err)
+	}
+	if u.Query().Get("api_key") != "secret" {
+		t.Errorf("api_key query param = %q, want secret", u.Query().Get("api_key"))
+	}
+	if u.Query().Get("existing") != "1" {
+		t.Error("expected the existing query parameter to survive")
+	}
+}
+
+func TestQueryAuth_InvalidURL(t *testing.T) {
+	ba := NewBaseAction(&http.Client{}, "GET", "http://example.com/%zz")
+
+	if err := NewQueryAuth("api_key", "secret").Authenticate(ba); err == nil {
+		t.Error("expected an error for an unparsable URL")
+	}
+}
+
+func TestJSONBodyAuth(t *testing.T) {
+	tests := []struct {
+		name        string
+		headerKey   string
+		contentType string
+		body        []byte
+		wantApplied bool
+	}{
+		{
+			name:        "exact application/json",
+			contentType: "application/json",
+			body:        []byte(`{"foo":"bar"}`),
+			wantApplied: true,
+		},
+		{
+			name:        "application/json with charset parameter",
+			contentType: "application/json; charset=utf-8",
+			body:        []byte(`{"foo":"bar"}`),
+			wantApplied: true,
+		},
+		{
+			name:        "case-insensitive content-type key and value",
+			headerKey:   "content-type",
+			contentType: "Application/JSON",
+			body:        []byte(`{"foo":"bar"}`),
+			wantApplied: true,
+		},
+		{
+			name:        "non-JSON content-type is skipped",
+			contentType: "text/plain",
+			body:        []byte(`irrelevant`),
+			wantApplied: false,
+		},
+		{
+			name:        "no content-type is skipped",
+			contentType: "",
+			body:        nil,
+			wantApplied: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ba := NewBaseAction(&http.Client{}, "POST", "http://example.com")
+			if tt.contentType != "" {
+				key := tt.headerKey
+				if key == "" {
+					key = "Content-Type"
+				}
+				ba.headers[key] = tt.contentType
+			}
+			ba.requestBodyBuffer = tt.body
+
+			if err := NewJSONBodyAuth("api_key", "secret").Authenticate(ba); err != nil {
+				t.Fatalf("Authenticate: %v", err)
+			}
+
+			applied := bytesContain(ba.requestBodyBuffer, `"api_key":"secret"`)
+			if applied != tt.wantApplied {
+				t.Errorf("body = %s, wantApplied = %v", ba.requestBodyBuffer, tt.wantApplied)
+			}
+		})
+	}
+}
+
+func TestJSONBodyAuth_EmptyBodyStartsAFreshObject(t *testing.T) {
+	ba := NewBaseAction(&http.Client{}, "POST", "http://example.com")
+	ba.headers["Content-Type"] = "application/json"
+
+	if err := NewJSONBodyAuth("api_key", "secret").Authenticate(ba); err != nil {
+		t.Fatalf("Authenticate: %v", err)
+	}
+	if !bytesContain(ba.requestBodyBuffer, `"api_key":"secret"`) {
+		t.Errorf("body = %s, want api_key injected into a fresh object", ba.requestBodyBuffer)
+	}
+}
+
+func TestJSONBodyAuth_MalformedBody(t *testing.T) {
+	ba := NewBaseAction(&http.Client{}, "POST", "http://example.com")
+	ba.headers["Content-Type"] = "application/json"
+	ba.requestBodyBuffer = []byte(`{not json`)
+
+	if err := NewJSONBodyAuth("api_key", "secret").Authenticate(ba); err == nil {
+		t.Error("expected an error for a malformed JSON body")
+	}
+}
+
+func bytesContain(haystack []byte, needle string) bool {
+	return len(haystack) > 0 && strings.Contains(string(haystack), needle)
+}
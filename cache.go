@@ -0,0 +1,399 @@
+package httption
+
+import (
+	"container/list"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// defaultCacheTTL is used when an entry is cached but no WithCacheTTL
+// option and no Cache-Control: max-age was supplied.
+const defaultCacheTTL = 5 * time.Minute
+
+// cacheStrippedHeaders lists request headers excluded from the default
+// cache key so that two requests differing only by credentials share a
+// cache entry.
+var cacheStrippedHeaders = []string{"Authorization", "Cookie", "Proxy-Authorization"}
+
+// CachedResponse is a stored response eligible for reuse by a later
+// BaseAction.do() call with the same cache key.
+type CachedResponse struct {
+	StatusCode int
+	Header     http.Header
+	Body       []byte
+
+	// vary holds the request header values named by this response's
+	// Vary header at the time it was cached, so a later hit can be
+	// rejected if those headers no longer match.
+	vary map[string]string
+}
+
+// ResponseCache is implemented by anything BaseAction can use to store
+// and retrieve previously seen responses.
+type ResponseCache interface {
+	Get(key string) (*CachedResponse, bool)
+	Set(key string, cr *CachedResponse, ttl time.Duration)
+	Invalidate(pattern string)
+}
+
+type cacheEntry struct {
+	key       string
+	cr        *CachedResponse
+	expiresAt time.Time
+	elem      *list.Element
+}
+
+// memoryResponseCache is a TTL-bounded, LRU-evicted in-memory
+// ResponseCache, returned by NewMemoryResponseCache. It is not installed
+// automatically: an action caches nothing unless WithResponseCache gives
+// it a backend, whatever WithCacheTTL or WithCacheKey say.
+type memoryResponseCache struct {
+	mu         sync.Mutex
+	maxEntries int
+	entries    map[string]*cacheEntry
+	order      *list.List // front = most recently used
+}
+
+// NewMemoryResponseCache returns a ResponseCache bounded to maxEntries,
+// evicting the least recently used entry once full.
+func NewMemoryResponseCache(maxEntries int) ResponseCache {
+	if maxEntries <= 0 {
+		maxEntries = 256
+	}
+
+	return &memoryResponseCache{
+		maxEntries: maxEntries,
+		entries:    make(map[string]*cacheEntry),
+		order:      list.New(),
+	}
+}
+
+func (c *memoryResponseCache) Get(key string) (*CachedResponse, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	e, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+
+	if time.Now().After(e.expiresAt) {
+		c.removeLocked(e)
+		return nil, false
+	}
+
+	c.order.MoveToFront(e.elem)
+
+	return e.cr, true
+}
+
+func (c *memoryResponseCache) Set(key string, cr *CachedResponse, ttl time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if ttl <= 0 {
+		ttl = defaultCacheTTL
+	}
+
+	if e, ok := c.entries[key]; ok {
+		e.cr = cr
+		e.expiresAt = time.Now().Add(ttl)
+		c.order.MoveToFront(e.elem)
+		return
+	}
+
+	e := &cacheEntry{key: key, cr: cr, expiresAt: time.Now().Add(ttl)}
+	e.elem = c.order.PushFront(e)
+	c.entries[key] = e
+
+	for len(c.entries) > c.maxEntries {
+		oldest := c.order.Back()
+		if oldest == nil {
+			break
+		}
+		c.removeLocked(oldest.Value.(*cacheEntry))
+	}
+}
+
+func (c *memoryResponseCache) Invalidate(pattern string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for key, e := range c.entries {
+		if pattern == "" || strings.Contains(key, pattern) {
+			c.removeLocked(e)
+		}
+	}
+}
+
+// removeLocked must be called with c.mu held.
+func (c *memoryResponseCache) removeLocked(e *cacheEntry) {
+	c.order.Remove(e.elem)
+	delete(c.entries, e.key)
+}
+
+// WithResponseCache enables response caching for an action using the
+// given cache backend.
+func WithResponseCache(cache ResponseCache) Option {
+	return func(ba *BaseAction) error {
+		ba.cache = cache
+		return nil
+	}
+}
+
+// WithCacheTTL sets the TTL applied to entries this action stores,
+// unless overridden by a Cache-Control: max-age on the response.
+func WithCacheTTL(d time.Duration) Option {
+	return func(ba *BaseAction) error {
+		ba.cacheTTL = d
+		return nil
+	}
+}
+
+// WithCacheKey overrides the default cache key derivation.
+func WithCacheKey(fn func(*http.Request) string) Option {
+	return func(ba *BaseAction) error {
+		ba.cacheKeyFunc = fn
+		return nil
+	}
+}
+
+// WithCacheUnsafeMethods opts an action using a non-GET/HEAD method into
+// caching. By default only safe methods are cached.
+func WithCacheUnsafeMethods() Option {
+	return func(ba *BaseAction) error {
+		ba.cacheUnsafeMethods = true
+		return nil
+	}
+}
+
+// InvalidateCache wipes cache entries matching pattern (substring match
+// against the cache key) from this action's cache backend, if any is
+// configured. Mutating actions sharing a cache with a related read
+// action can use this to evict stale entries after a write.
+func (ba *BaseAction) InvalidateCache(pattern string) {
+	if ba.cache == nil {
+		return
+	}
+
+	ba.cache.Invalidate(pattern)
+}
+
+func (ba *BaseAction) cacheKey(req *http.Request) string {
+	if ba.cacheKeyFunc != nil {
+		return ba.cacheKeyFunc(req)
+	}
+
+	return defaultCacheKey(req)
+}
+
+func defaultCacheKey(req *http.Request) string {
+	var b strings.Builder
+
+	b.WriteString(req.Method)
+	b.WriteByte('\n')
+	b.WriteString(req.URL.Scheme)
+	b.WriteString("://")
+	b.WriteString(req.URL.Host)
+	b.WriteString(req.URL.Path)
+	b.WriteByte('\n')
+	b.WriteString(sortedQuery(req.URL))
+	b.WriteByte('\n')
+	b.WriteString(strippedHeadersDigest(req.Header))
+
+	sum := sha256.Sum256([]byte(b.String()))
+
+	return hex.EncodeToString(sum[:])
+}
+
+func sortedQuery(u *url.URL) string {
+	q := u.Query()
+	names := make([]string, 0, len(q))
+	for n := range q {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+
+	var b strings.Builder
+	for _, n := range names {
+		vals := q[n]
+		sort.Strings(vals)
+		for _, v := range vals {
+			b.WriteString(n)
+			b.WriteByte('=')
+			b.WriteString(v)
+			b.WriteByte('&')
+		}
+	}
+
+	return b.String()
+}
+
+func strippedHeadersDigest(h http.Header) string {
+	names := make([]string, 0, len(h))
+	for n := range h {
+		if isStrippedCacheHeader(n) {
+			continue
+		}
+		names = append(names, n)
+	}
+	sort.Strings(names)
+
+	var b strings.Builder
+	for _, n := range names {
+		vals := append([]string(nil), h[n]...)
+		sort.Strings(vals)
+		b.WriteString(n)
+		b.WriteByte(':')
+		b.WriteString(strings.Join(vals, ","))
+		b.WriteByte(';')
+	}
+
+	return b.String()
+}
+
+func isStrippedCacheHeader(name string) bool {
+	for _, n := range cacheStrippedHeaders {
+		if strings.EqualFold(n, name) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// isSafeCacheMethod reports whether method is cacheable by default
+// (GET/HEAD), mirroring HTTP's notion of a safe method.
+func isSafeCacheMethod(method string) bool {
+	return method == http.MethodGet || method == http.MethodHead
+}
+
+// cacheControlDirectives parses a Cache-Control header into a
+// lower-cased directive -> value map (value is empty for valueless
+// directives such as no-store).
+func cacheControlDirectives(h http.Header) map[string]string {
+	directives := make(map[string]string)
+
+	for _, part := range strings.Split(h.Get("Cache-Control"), ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		kv := strings.SplitN(part, "=", 2)
+		name := strings.ToLower(strings.TrimSpace(kv[0]))
+		value := ""
+		if len(kv) == 2 {
+			value = strings.Trim(strings.TrimSpace(kv[1]), `"`)
+		}
+
+		directives[name] = value
+	}
+
+	return directives
+}
+
+// varySnapshot captures the request header values named by the
+// response's Vary header, so a later cache hit can be rejected if the
+// new request no longer matches them.
+func varySnapshot(respHeader, reqHeader http.Header) map[string]string {
+	vary := respHeader.Get("Vary")
+	if vary == "" {
+		return nil
+	}
+
+	snapshot := make(map[string]string)
+	for _, name := range strings.Split(vary, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" || name == "*" {
+			continue
+		}
+		snapshot[name] = reqHeader.Get(name)
+	}
+
+	return snapshot
+}
+
+func varyMatches(vary map[string]string, reqHeader http.Header) bool {
+	for name, val := range vary {
+		if reqHeader.Get(name) != val {
+			return false
+		}
+	}
+
+	return true
+}
+
+// tryCacheHit checks ba.cache for a usable entry for req and, on a hit,
+// populates ba.response and ba.responseBodyBuffer directly.
+func (ba *BaseAction) tryCacheHit(req *http.Request) bool {
+	if ba.cache == nil || !ba.cacheable(req.Method) {
+		return false
+	}
+
+	cr, ok := ba.cache.Get(ba.cacheKey(req))
+	if !ok {
+		return false
+	}
+
+	if !varyMatches(cr.vary, req.Header) {
+		return false
+	}
+
+	ba.response = &http.Response{
+		StatusCode: cr.StatusCode,
+		Status:     strconv.Itoa(cr.StatusCode) + " " + http.StatusText(cr.StatusCode),
+		Header:     cr.Header.Clone(),
+		Request:    req,
+	}
+	ba.responseBodyBuffer = cr.Body
+
+	return true
+}
+
+// maybeStoreInCache caches a 2xx response to a cacheable request unless
+// Cache-Control: no-store is present.
+func (ba *BaseAction) maybeStoreInCache(req *http.Request, resp *http.Response, body []byte) {
+	if ba.cache == nil || !ba.cacheable(req.Method) {
+		return
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return
+	}
+
+	directives := cacheControlDirectives(resp.Header)
+	if _, noStore := directives["no-store"]; noStore {
+		return
+	}
+
+	ttl := ba.cacheTTL
+	if maxAge, ok := directives["max-age"]; ok {
+		if secs, err := strconv.Atoi(maxAge); err == nil {
+			if secs <= 0 {
+				return
+			}
+			ttl = time.Duration(secs) * time.Second
+		}
+	}
+
+	cr := &CachedResponse{
+		StatusCode: resp.StatusCode,
+		Header:     resp.Header.Clone(),
+		Body:       body,
+		vary:       varySnapshot(resp.Header, req.Header),
+	}
+
+	ba.cache.Set(ba.cacheKey(req), cr, ttl)
+}
+
+func (ba *BaseAction) cacheable(method string) bool {
+	return isSafeCacheMethod(method) || ba.cacheUnsafeMethods
+}
@@ -0,0 +1,269 @@
+package httption
+
+import (
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestMemoryResponseCache_LRUEviction(t *testing.T) {
+	c := NewMemoryResponseCache(2)
+
+	c.Set("a", &CachedResponse{StatusCode: 200}, time.Minute)
+	c.Set("b", &CachedResponse{StatusCode: 200}, time.Minute)
+
+	// touch "a" so "b" becomes the least recently used entry.
+	if _, ok := c.Get("a"); !ok {
+		t.Fatal("expected \"a\" to be present before eviction")
+	}
+
+	c.Set("c", &CachedResponse{StatusCode: 200}, time.Minute)
+
+	if _, ok := c.Get("b"); ok {
+		t.Error("expected \"b\" to be evicted as least recently used")
+	}
+	if _, ok := c.Get("a"); !ok {
+		t.Error("expected \"a\" to survive eviction (recently touched)")
+	}
+	if _, ok := c.Get("c"); !ok {
+		t.Error("expected \"c\" to be present (just inserted)")
+	}
+}
+
+func TestMemoryResponseCache_TTLExpiry(t *testing.T) {
+	c := NewMemoryResponseCache(10)
+
+	c.Set("k", &CachedResponse{StatusCode: 200}, 10*time.Millisecond)
+
+	if _, ok := c.Get("k"); !ok {
+		t.Fatal("expected entry to be present before its TTL elapses")
+	}
+
+	time.Sleep(20 * time.Millisecond)
+
+	if _, ok := c.Get("k"); ok {
+		t.Error("expected entry to be gone after its TTL elapses")
+	}
+}
+
+func TestMemoryResponseCache_Invalidate(t *testing.T) {
+	c := NewMemoryResponseCache(10)
+
+	c.Set("users/1", &CachedResponse{StatusCode: 200}, time.Minute)
+	c.Set("users/2", &CachedResponse{StatusCode: 200}, time.Minute)
+	c.Set("orders/1", &CachedResponse{StatusCode: 200}, time.Minute)
+
+	c.Invalidate("users/")
+
+	if _, ok := c.Get("users/1"); ok {
+		t.Error("expected \"users/1\" to be invalidated")
+	}
+	if _, ok := c.Get("users/2"); ok {
+		t.Error("expected \"users/2\" to be invalidated")
+	}
+	if _, ok := c.Get("orders/1"); !ok {
+		t.Error("expected \"orders/1\" to survive an unrelated pattern")
+	}
+}
+
+func mustRequest(t *testing.T, method, rawURL string, headers map[string][]string) *http.Request {
+	t.Helper()
+
+	req, err := http.NewRequest(method, rawURL, nil)
+	if err != nil {
+		t.Fatalf("http.NewRequest: %v", err)
+	}
+
+	for name, values := range headers {
+		for _, v := range values {
+			req.Header.Add(name, v)
+		}
+	}
+
+	return req
+}
+
+func TestDefaultCacheKey(t *testing.T) {
+	base := mustRequest(t, "GET", "http://example.com/things?b=2&a=1", nil)
+	sameQueryDifferentOrder := mustRequest(t, "GET", "http://example.com/things?a=1&b=2", nil)
+	differentAuth := mustRequest(t, "GET", "http://example.com/things?a=1&b=2", map[string][]string{
+		"Authorization": {"Bearer token-a"},
+	})
+	differentAuthOther := mustRequest(t, "GET", "http://example.com/things?a=1&b=2", map[string][]string{
+		"Authorization": {"Bearer token-b"},
+	})
+	differentPath := mustRequest(t, "GET", "http://example.com/other?a=1&b=2", nil)
+	differentHeader := mustRequest(t, "GET", "http://example.com/things?a=1&b=2", map[string][]string{
+		"Accept-Language": {"en"},
+	})
+
+	if defaultCacheKey(base) != defaultCacheKey(sameQueryDifferentOrder) {
+		t.Error("expected query parameter order not to affect the cache key")
+	}
+
+	if defaultCacheKey(differentAuth) != defaultCacheKey(base) {
+		t.Error("expected Authorization to be stripped from the cache key")
+	}
+
+	if defaultCacheKey(differentAuth) != defaultCacheKey(differentAuthOther) {
+		t.Error("expected two requests differing only by Authorization to share a cache key")
+	}
+
+	if defaultCacheKey(differentPath) == defaultCacheKey(base) {
+		t.Error("expected a different path to produce a different cache key")
+	}
+
+	if defaultCacheKey(differentHeader) == defaultCacheKey(base) {
+		t.Error("expected a non-stripped header to affect the cache key")
+	}
+}
+
+func TestVaryMatches(t *testing.T) {
+	tests := []struct {
+		name string
+		vary map[string]string
+		req  http.Header
+		want bool
+	}{
+		{
+			name: "no vary constraints",
+			vary: nil,
+			req:  http.Header{},
+			want: true,
+		},
+		{
+			name: "matching header",
+			vary: map[string]string{"Accept-Language": "en"},
+			req:  http.Header{"Accept-Language": {"en"}},
+			want: true,
+		},
+		{
+			name: "mismatched header",
+			vary: map[string]string{"Accept-Language": "en"},
+			req:  http.Header{"Accept-Language": {"fr"}},
+			want: false,
+		},
+		{
+			name: "missing header treated as empty value",
+			vary: map[string]string{"Accept-Language": ""},
+			req:  http.Header{},
+			want: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := varyMatches(tt.vary, tt.req); got != tt.want {
+				t.Errorf("varyMatches() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCacheControlDirectives(t *testing.T) {
+	tests := []struct {
+		name   string
+		header string
+		want   map[string]string
+	}{
+		{
+			name:   "empty",
+			header: "",
+			want:   map[string]string{},
+		},
+		{
+			name:   "no-store",
+			header: "no-store",
+			want:   map[string]string{"no-store": ""},
+		},
+		{
+			name:   "max-age with spaces",
+			header: "public, max-age = 120",
+			want:   map[string]string{"public": "", "max-age": "120"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := http.Header{}
+			h.Set("Cache-Control", tt.header)
+
+			got := cacheControlDirectives(h)
+			if len(got) != len(tt.want) {
+				t.Fatalf("cacheControlDirectives() = %v, want %v", got, tt.want)
+			}
+			for k, v := range tt.want {
+				if got[k] != v {
+					t.Errorf("directive %q = %q, want %q", k, got[k], v)
+				}
+			}
+		})
+	}
+}
+
+func TestBaseAction_CacheHitAndStore(t *testing.T) {
+	ba := NewBaseAction(&http.Client{}, "GET", "http://example.com/things")
+	ba.cache = NewMemoryResponseCache(10)
+
+	req, err := http.NewRequest("GET", "http://example.com/things", nil)
+	if err != nil {
+		t.Fatalf("http.NewRequest: %v", err)
+	}
+
+	if ba.tryCacheHit(req) {
+		t.Fatal("expected no cache hit before anything is stored")
+	}
+
+	resp := &http.Response{
+		StatusCode: 200,
+		Header:     http.Header{},
+		Request:    req,
+	}
+	ba.maybeStoreInCache(req, resp, []byte(`{"ok":true}`))
+
+	if !ba.tryCacheHit(req) {
+		t.Fatal("expected a cache hit for an identical request after storing")
+	}
+	if string(ba.responseBodyBuffer) != `{"ok":true}` {
+		t.Errorf("responseBodyBuffer = %q, want %q", ba.responseBodyBuffer, `{"ok":true}`)
+	}
+}
+
+func TestBaseAction_CacheStore_NoStoreDirective(t *testing.T) {
+	ba := NewBaseAction(&http.Client{}, "GET", "http://example.com/things")
+	ba.cache = NewMemoryResponseCache(10)
+
+	req, _ := http.NewRequest("GET", "http://example.com/things", nil)
+
+	resp := &http.Response{
+		StatusCode: 200,
+		Header:     http.Header{"Cache-Control": {"no-store"}},
+		Request:    req,
+	}
+	ba.maybeStoreInCache(req, resp, []byte("body"))
+
+	if ba.tryCacheHit(req) {
+		t.Error("expected Cache-Control: no-store to prevent caching")
+	}
+}
+
+func TestBaseAction_CacheStore_UnsafeMethodRequiresOptIn(t *testing.T) {
+	ba := NewBaseAction(&http.Client{}, "POST", "http://example.com/things")
+	ba.cache = NewMemoryResponseCache(10)
+
+	req, _ := http.NewRequest("POST", "http://example.com/things", nil)
+
+	resp := &http.Response{StatusCode: 200, Header: http.Header{}, Request: req}
+	ba.maybeStoreInCache(req, resp, []byte("body"))
+
+	if ba.tryCacheHit(req) {
+		t.Error("expected a POST not to be cached without WithCacheUnsafeMethods")
+	}
+
+	ba.cacheUnsafeMethods = true
+	ba.maybeStoreInCache(req, resp, []byte("body"))
+
+	if !ba.tryCacheHit(req) {
+		t.Error("expected a POST to be cached once opted in via WithCacheUnsafeMethods")
+	}
+}
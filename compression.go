@@ -0,0 +1,68 @@
+package httption
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/sik0-o/httption/httpclient"
+)
+
+// WithCompression enables transparent response decompression and
+// advertises the given encodings in an Accept-Encoding header on the
+// outgoing request unless the caller already set one. With no encodings
+// given, httpclient's defaults (gzip, deflate) are advertised. Responses
+// encoded as gzip, deflate or zlib are decoded whichever encodings were
+// given; other encodings are passed through undecoded.
+func WithCompression(encodings ...string) Option {
+	return func(ba *BaseAction) error {
+		return ba.SetupCompression(encodings)
+	}
+}
+
+// WithoutCompression disables response decompression previously enabled
+// by WithCompression.
+func WithoutCompression() Option {
+	return func(ba *BaseAction) error {
+		return ba.SetupNoCompression()
+	}
+}
+
+// SetupCompression wraps the action's transport in a
+// httpclient.DecompressingRoundTripper (or updates the encodings of one
+// already installed) so the io.ReadAll in BaseAction.do() sees decoded
+// bytes, as does an httpclient.LoggingRoundTripper that wraps it.
+func (ba *BaseAction) SetupCompression(encodings []string) error {
+	switch t := ba.client.Transport.(type) {
+	case *httpclient.DecompressingRoundTripper:
+		t.Encodings = encodings
+	case *httpclient.LoggingRoundTripper:
+		if drt, ok := t.Proxied.(*httpclient.DecompressingRoundTripper); ok {
+			drt.Encodings = encodings
+		} else {
+			t.Proxied = &httpclient.DecompressingRoundTripper{Proxied: t.Proxied, Encodings: encodings}
+		}
+	case *http.Transport:
+		ba.client.Transport = &httpclient.DecompressingRoundTripper{Proxied: t, Encodings: encodings}
+	case nil:
+		ba.client.Transport = &httpclient.DecompressingRoundTripper{Proxied: http.DefaultTransport, Encodings: encodings}
+	default:
+		return errors.New("BaseAction.SetupCompression() cannot set compression because transport has unknown type")
+	}
+
+	return nil
+}
+
+// SetupNoCompression removes any DecompressingRoundTripper installed by
+// WithCompression, restoring the transport it wrapped.
+func (ba *BaseAction) SetupNoCompression() error {
+	switch t := ba.client.Transport.(type) {
+	case *httpclient.DecompressingRoundTripper:
+		ba.client.Transport = t.Proxied
+	case *httpclient.LoggingRoundTripper:
+		if drt, ok := t.Proxied.(*httpclient.DecompressingRoundTripper); ok {
+			t.Proxied = drt.Proxied
+		}
+	}
+
+	return nil
+}
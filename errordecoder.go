@@ -0,0 +1,133 @@
+package httption
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+)
+
+// ErrorDecoder turns a non-2xx (or otherwise handler-flagged) response
+// into an error. It runs after status-code handlers, once per attempt.
+type ErrorDecoder interface {
+	Decode(resp *http.Response, body []byte) error
+}
+
+// ErrorDecoderFunc adapts a plain func to ErrorDecoder.
+type ErrorDecoderFunc func(resp *http.Response, body []byte) error
+
+func (f ErrorDecoderFunc) Decode(resp *http.Response, body []byte) error {
+	return f(resp, body)
+}
+
+// HTTPError is a generic non-2xx response error carrying enough context
+// for a caller to decide how to handle it without re-parsing the
+// response. Returned by StatusErrorDecoder.
+type HTTPError struct {
+	StatusCode int
+	Status     string
+	URL        string
+	Body       []byte
+	Header     http.Header
+}
+
+func (e *HTTPError) Error() string {
+	return fmt.Sprintf("%s: %s: %s", e.URL, e.Status, string(e.Body))
+}
+
+// NewStatusErrorDecoder returns an ErrorDecoder that wraps any non-2xx
+// response in an *HTTPError, with no domain-specific interpretation of
+// the body.
+func NewStatusErrorDecoder() ErrorDecoder {
+	return ErrorDecoderFunc(func(resp *http.Response, body []byte) error {
+		if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+			return nil
+		}
+
+		return newHTTPError(resp, body)
+	})
+}
+
+func newHTTPError(resp *http.Response, body []byte) *HTTPError {
+	url := ""
+	if resp.Request != nil && resp.Request.URL != nil {
+		url = resp.Request.URL.String()
+	}
+
+	return &HTTPError{
+		StatusCode: resp.StatusCode,
+		Status:     resp.Status,
+		URL:        url,
+		Body:       body,
+		Header:     resp.Header,
+	}
+}
+
+// jsonErrorDecoder decodes a non-2xx JSON body into a fresh copy of a
+// user-provided struct pointer and hands it to mapper to produce the
+// final error.
+type jsonErrorDecoder struct {
+	target any
+	mapper func(decoded any, status int) error
+}
+
+// NewJSONErrorDecoder returns an ErrorDecoder that, for any non-2xx
+// JSON response, unmarshals the body into a fresh value shaped like
+// target (a pointer, e.g. &MyAPIError{}) and calls mapper with it and
+// the response's status code to produce the error to return.
+func NewJSONErrorDecoder(target any, mapper func(decoded any, status int) error) ErrorDecoder {
+	return &jsonErrorDecoder{target: target, mapper: mapper}
+}
+
+func (d *jsonErrorDecoder) Decode(resp *http.Response, body []byte) error {
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return nil
+	}
+
+	decoded := reflect.New(reflect.TypeOf(d.target).Elem()).Interface()
+
+	if len(body) > 0 && strings.Contains(resp.Header.Get("Content-Type"), "application/json") {
+		if err := json.Unmarshal(body, decoded); err != nil {
+			return err
+		}
+	}
+
+	return d.mapper(decoded, resp.StatusCode)
+}
+
+// WithErrorDecoder overrides the ErrorDecoder an action uses to turn a
+// non-2xx response into an error. Without this option, an action uses
+// defaultErrorDecoder.
+func WithErrorDecoder(decoder ErrorDecoder) Option {
+	return func(ba *BaseAction) error {
+		ba.errorDecoder = decoder
+		return nil
+	}
+}
+
+// defaultErrorDecoder preserves this package's historical behavior of
+// short-circuiting 429 into ErrTooManyRequests and otherwise treating
+// any non-2xx status as a generic *HTTPError. It no longer carries the
+// payment-specific mapping that used to live here; callers outside that
+// original vertical should reach for WithErrorDecoder instead of
+// patching this function.
+var defaultErrorDecoder = ErrorDecoderFunc(func(resp *http.Response, body []byte) error {
+	if resp.StatusCode == http.StatusTooManyRequests {
+		return ErrTooManyRequests
+	}
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return nil
+	}
+
+	return newHTTPError(resp, body)
+})
+
+func (ba *BaseAction) errorDecoderOrDefault() ErrorDecoder {
+	if ba.errorDecoder != nil {
+		return ba.errorDecoder
+	}
+
+	return defaultErrorDecoder
+}
@@ -0,0 +1,148 @@
+package httption
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+)
+
+func TestStatusErrorDecoder(t *testing.T) {
+	decoder := NewStatusErrorDecoder()
+
+	t.Run("2xx is not an error", func(t *testing.T) {
+		resp := &http.Response{StatusCode: 200}
+		if err := decoder.Decode(resp, nil); err != nil {
+			t.Errorf("Decode() = %v, want nil", err)
+		}
+	})
+
+	t.Run("non-2xx wraps an HTTPError", func(t *testing.T) {
+		req, _ := http.NewRequest("GET", "http://example.com/things", nil)
+		resp := &http.Response{
+			StatusCode: 404,
+			Status:     "404 Not Found",
+			Header:     http.Header{"X-Trace-Id": {"abc"}},
+			Request:    req,
+		}
+
+		err := decoder.Decode(resp, []byte("not found"))
+		if err == nil {
+			t.Fatal("expected an error for a 404 response")
+		}
+
+		var httpErr *HTTPError
+		if !errors.As(err, &httpErr) {
+			t.Fatalf("error = %v (%T), want *HTTPError", err, err)
+		}
+		if httpErr.StatusCode != 404 || httpErr.URL != "http://example.com/things" || string(httpErr.Body) != "not found" {
+			t.Errorf("HTTPError = %+v, unexpected fields", httpErr)
+		}
+	})
+
+	t.Run("request-less response still decodes", func(t *testing.T) {
+		resp := &http.Response{StatusCode: 500, Status: "500 Internal Server Error"}
+
+		err := decoder.Decode(resp, nil)
+		var httpErr *HTTPError
+		if !errors.As(err, &httpErr) || httpErr.URL != "" {
+			t.Errorf("error = %v, want an *HTTPError with empty URL", err)
+		}
+	})
+}
+
+type apiError struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+func TestJSONErrorDecoder(t *testing.T) {
+	decoder := NewJSONErrorDecoder(&apiError{}, func(decoded any, status int) error {
+		e := decoded.(*apiError)
+		return errors.New(e.Code + ":" + e.Message)
+	})
+
+	t.Run("2xx is not an error", func(t *testing.T) {
+		resp := &http.Response{StatusCode: 200}
+		if err := decoder.Decode(resp, []byte(`{"code":"x"}`)); err != nil {
+			t.Errorf("Decode() = %v, want nil", err)
+		}
+	})
+
+	t.Run("non-2xx JSON body is unmarshalled before mapping", func(t *testing.T) {
+		resp := &http.Response{
+			StatusCode: 400,
+			Header:     http.Header{"Content-Type": {"application/json; charset=utf-8"}},
+		}
+
+		err := decoder.Decode(resp, []byte(`{"code":"bad_request","message":"missing field"}`))
+		if err == nil || err.Error() != "bad_request:missing field" {
+			t.Errorf("err = %v, want \"bad_request:missing field\"", err)
+		}
+	})
+
+	t.Run("non-JSON content-type skips unmarshal but still maps", func(t *testing.T) {
+		resp := &http.Response{
+			StatusCode: 500,
+			Header:     http.Header{"Content-Type": {"text/plain"}},
+		}
+
+		err := decoder.Decode(resp, []byte("boom"))
+		if err == nil || err.Error() != ":" {
+			t.Errorf("err = %v, want mapper called with a zero-value apiError", err)
+		}
+	})
+
+	t.Run("malformed JSON body surfaces the unmarshal error", func(t *testing.T) {
+		resp := &http.Response{
+			StatusCode: 400,
+			Header:     http.Header{"Content-Type": {"application/json"}},
+		}
+
+		err := decoder.Decode(resp, []byte(`{not json`))
+		if err == nil {
+			t.Error("expected an unmarshal error to be surfaced")
+		}
+	})
+}
+
+func TestDefaultErrorDecoder(t *testing.T) {
+	t.Run("429 maps to ErrTooManyRequests", func(t *testing.T) {
+		resp := &http.Response{StatusCode: http.StatusTooManyRequests}
+		if err := defaultErrorDecoder.Decode(resp, nil); !errors.Is(err, ErrTooManyRequests) {
+			t.Errorf("err = %v, want ErrTooManyRequests", err)
+		}
+	})
+
+	t.Run("2xx is not an error", func(t *testing.T) {
+		resp := &http.Response{StatusCode: 204}
+		if err := defaultErrorDecoder.Decode(resp, nil); err != nil {
+			t.Errorf("Decode() = %v, want nil", err)
+		}
+	})
+
+	t.Run("other non-2xx wraps an HTTPError", func(t *testing.T) {
+		resp := &http.Response{StatusCode: 503, Status: "503 Service Unavailable"}
+
+		err := defaultErrorDecoder.Decode(resp, []byte("down"))
+		var httpErr *HTTPError
+		if !errors.As(err, &httpErr) || httpErr.StatusCode != 503 {
+			t.Errorf("err = %v, want an *HTTPError with StatusCode 503", err)
+		}
+	})
+}
+
+func TestBaseAction_ErrorDecoderOrDefault(t *testing.T) {
+	resp := &http.Response{StatusCode: http.StatusTooManyRequests}
+
+	ba := NewBaseAction(&http.Client{}, "GET", "http://example.com")
+	if err := ba.errorDecoderOrDefault().Decode(resp, nil); !errors.Is(err, ErrTooManyRequests) {
+		t.Errorf("err = %v, want ErrTooManyRequests from the package default", err)
+	}
+
+	ba.errorDecoder = NewStatusErrorDecoder()
+	err := ba.errorDecoderOrDefault().Decode(resp, nil)
+	var httpErr *HTTPError
+	if !errors.As(err, &httpErr) {
+		t.Errorf("err = %v, want an *HTTPError from the configured decoder taking precedence", err)
+	}
+}
@@ -2,6 +2,7 @@ package httption
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"io"
@@ -16,9 +17,10 @@ import (
 // HttpAction is an interface to perform http requests and handle its responses.
 // It has beforeAction and afterAction callbacks that it is also HttpActions.
 type HttpAction interface {
-	Do(opts ...Option) error         // perform http action
-	Result(result interface{}) error // unmarshal result to struct
-	Error() error                    // return last action error
+	Do(opts ...Option) error                             // perform http action
+	DoContext(ctx context.Context, opts ...Option) error // perform http action, cancellable via ctx
+	Result(result interface{}) error                     // unmarshal result to struct
+	Error() error                                        // return last action error
 }
 
 var (
@@ -32,9 +34,6 @@ var ErrEmptyRequest = errors.New("action request is empty. Do Setup() method bef
 
 type BaseAction struct {
 	name string
-	// Callbacks
-	// beforeAction []HttpAction
-	// afterAction  []HttpAction
 
 	// HTTP-params
 	client             *http.Client
@@ -45,17 +44,40 @@ type BaseAction struct {
 	requestBodyBuffer  []byte
 	responseBodyBuffer []byte
 	headers            map[string]string
+	authenticators     []Authenticator
+
+	// Response cache
+	cache              ResponseCache
+	cacheTTL           time.Duration
+	cacheKeyFunc       func(*http.Request) string
+	cacheUnsafeMethods bool
+
+	// Middleware chain, built once per Setup from middleware. The
+	// lifecycle hooks below run outside the chain.
+	middleware    []Middleware
+	beforeRequest []BeforeRequestFunc
+	afterResponse []AfterResponseFunc
+	onRetry       []RetryFunc
+	handler       Handler
 
 	logger *zap.Logger
 
 	// Response Handlers
 	statusCodeHandlers map[int]StatusCodeHandlerFunc
+	errorDecoder       ErrorDecoder
 
 	// Retry
-	tryCount   uint
-	maxRetry   uint
-	needRetry  bool
-	retryDelay time.Duration
+	tryCount          uint
+	maxRetry          uint
+	needRetry         bool
+	retryDelay        time.Duration
+	backoff           BackoffStrategy
+	retryAfter        time.Duration
+	perAttemptTimeout time.Duration
+	totalTimeout      time.Duration
+
+	// Context for the current/last Do(Context) call.
+	ctx context.Context
 
 	// Misc
 	err error
@@ -69,15 +91,14 @@ func NewBaseAction(client *http.Client, method string, url string) *BaseAction {
 	l, _ := zap.NewDevelopment()
 
 	return &BaseAction{
-		// beforeAction: []HttpAction{},
-		// afterAction:  []HttpAction{},
-
 		method: method,
 		url:    url,
 
 		client:  client,
 		headers: make(map[string]string),
 
+		ctx: context.Background(),
+
 		logger: l,
 	}
 }
@@ -121,8 +142,15 @@ func (ba *BaseAction) Setup(opts ...Option) error {
 		}
 	}
 
+	for _, a := range ba.authenticators {
+		if err := a.Authenticate(ba); err != nil {
+			ba.err = err
+			return err
+		}
+	}
+
 	if ba.request == nil {
-		req, err := prepareRequest(ba.method, ba.url, ba.requestBodyBuffer, ba.headers)
+		req, err := prepareRequest(ba.ctx, ba.method, ba.url, ba.requestBodyBuffer, ba.headers)
 		ba.err = err
 		if err != nil {
 			return err
@@ -131,6 +159,8 @@ func (ba *BaseAction) Setup(opts ...Option) error {
 		ba.request = req
 	}
 
+	ba.handler = ba.buildHandler()
+
 	return nil
 }
 
@@ -146,13 +176,31 @@ func (ba *BaseAction) Repeat(setupAction bool, opts ...Option) error {
 	return ba.do()
 }
 
+// Do performs the action with a background context. Use DoContext to
+// bound or cancel it.
 func (ba *BaseAction) Do(opts ...Option) error {
+	return ba.DoContext(context.Background(), opts...)
+}
+
+// DoContext performs the action, aborting immediately if ctx is
+// cancelled (between attempts, or mid-attempt via WithTimeout/
+// WithTotalTimeout).
+func (ba *BaseAction) DoContext(ctx context.Context, opts ...Option) error {
+	ba.ctx = ctx
+
 	ba.log(zap.DebugLevel, "action Do() Setup")
 	if err := ba.Setup(opts...); err != nil {
 		ba.log(zap.ErrorLevel, "action Do() Setup", zap.Error(err))
 		return err
 	}
 
+	runCtx := ctx
+	if ba.totalTimeout > 0 {
+		var cancel context.CancelFunc
+		runCtx, cancel = context.WithTimeout(ctx, ba.totalTimeout)
+		defer cancel()
+	}
+
 	var retry bool
 
 	// Next just make a do
@@ -162,24 +210,35 @@ func (ba *BaseAction) Do(opts ...Option) error {
 		} else {
 			retry = false
 		}
-		err := ba.do()
+		err := ba.doWithContext(runCtx)
 
 		if err == nil {
 			retry = false
 			// no errors -> quit cycle.
 			break
 		} else {
-			// when error and we have no retry -> throw error
+			// when error and we have no retry, or it isn't retryable -> throw error
 			ba.log(zap.ErrorLevel, "action Do() do", zap.Error(err))
-			// exit when no retry
-			if !retry {
+			if !retry || !ba.isRetryableError(err) {
 				return err
 			}
+
+			if ba.runOnRetry(ba.tryCount, err) {
+				ba.log(zap.DebugLevel, "retry aborted by OnRetry hook")
+				return err
+			}
+		}
+
+		// else process again, waiting for the computed backoff or an
+		// early cancellation, whichever comes first.
+		delay := ba.nextRetryDelay(ba.tryCount)
+		if delay > 0 {
+			ba.log(zap.DebugLevel, "waiting before retry", zap.Duration("delay", delay))
 		}
-		// else process again
-		if ba.retryDelay > 0 {
-			ba.log(zap.DebugLevel, "waiting before retry", zap.Duration("delay", ba.retryDelay))
-			<-time.After(ba.retryDelay)
+		select {
+		case <-runCtx.Done():
+			return runCtx.Err()
+		case <-time.After(delay):
 		}
 	}
 
@@ -214,16 +273,15 @@ func (ha *BaseAction) ResponseBytes() []byte {
 	return ha.responseBodyBuffer
 }
 
+// do runs a single attempt using the context captured by the last
+// Do/DoContext call.
 func (ba *BaseAction) do() error {
+	return ba.doWithContext(ba.ctx)
+}
+
+func (ba *BaseAction) doWithContext(ctx context.Context) error {
 
 	ba.tryCount++
-	// if len(ha.beforeAction) > 0 {
-	// 	for _, bact := range ha.beforeAction {
-	// 		if err := bact.Do(opts...); err != nil {
-	// 			return err
-	// 		}
-	// 	}
-	// }
 
 	ba.log(zap.DebugLevel, "do action", zap.Uint("attempt", ba.tryCount))
 
@@ -231,14 +289,40 @@ func (ba *BaseAction) do() error {
 		return ErrEmptyRequest
 	}
 
+	attemptCtx := ctx
+	if ba.perAttemptTimeout > 0 {
+		var cancel context.CancelFunc
+		attemptCtx, cancel = context.WithTimeout(ctx, ba.perAttemptTimeout)
+		defer cancel()
+	}
+
+	req := ba.request.WithContext(attemptCtx)
+
+	if err := ba.runBeforeRequest(req); err != nil {
+		ba.err = err
+		return err
+	}
+
+	if ba.tryCacheHit(req) {
+		ba.log(zap.DebugLevel, "response served from cache")
+
+		if err := ba.runAfterResponse(ba.response, ba.responseBodyBuffer); err != nil {
+			ba.err = err
+			return err
+		}
+
+		return nil
+	}
+
 	ba.log(zap.DebugLevel, "sending request")
-	resp, err := ba.client.Do(ba.request)
+	resp, err := ba.handler(attemptCtx, req)
 	ba.err = err
 	if err != nil {
 		return err
 	}
 	ba.log(zap.DebugLevel, "response received")
 	ba.response = resp
+	ba.retryAfter = retryAfterDuration(resp)
 
 	respBody, err := io.ReadAll(resp.Body)
 	if err != nil {
@@ -254,38 +338,32 @@ func (ba *BaseAction) do() error {
 		}
 	}
 
-	err = handleResponse(resp, respBody)
+	err = ba.errorDecoderOrDefault().Decode(resp, respBody)
 	ba.err = err
 	if err != nil {
-		if resp.StatusCode == http.StatusTooManyRequests {
-			return ErrTooManyRequests
-		}
-
 		return err
 	}
 
 	ba.responseBodyBuffer = respBody
+	ba.maybeStoreInCache(req, resp, respBody)
 
-	ba.log(zap.DebugLevel, "response handled")
+	if err := ba.runAfterResponse(resp, respBody); err != nil {
+		ba.err = err
+		return err
+	}
 
-	// if len(ha.afterAction) > 0 {
-	// 	for _, aact := range ha.afterAction {
-	// 		if err := aact.Do(opts...); err != nil {
-	// 			return err
-	// 		}
-	// 	}
-	// }
+	ba.log(zap.DebugLevel, "response handled")
 
 	return nil
 }
 
-func prepareRequest(method string, url string, bodyBytes []byte, headers map[string]string) (*http.Request, error) {
+func prepareRequest(ctx context.Context, method string, url string, bodyBytes []byte, headers map[string]string) (*http.Request, error) {
 	var bodyReader io.Reader
 	if bodyBytes != nil {
 		bodyReader = bytes.NewReader(bodyBytes)
 	}
 
-	req, err := http.NewRequest(method, url, bodyReader)
+	req, err := http.NewRequestWithContext(ctx, method, url, bodyReader)
 	if err != nil {
 		return nil, err
 	}
@@ -297,47 +375,6 @@ func prepareRequest(method string, url string, bodyBytes []byte, headers map[str
 	return req, nil
 }
 
-func handleResponse(resp *http.Response, respBody []byte) error {
-	if resp.StatusCode == http.StatusTooManyRequests {
-		return ErrTooManyRequests
-	}
-
-	if resp.StatusCode == http.StatusBadRequest {
-		if respBody != nil {
-			if resp.Header.Get("content-type") == "application/json" {
-				respData := map[string]any{}
-				if err := json.Unmarshal(respBody, &respData); err != nil {
-					return err
-				}
-
-				code, _ := respData["code"].(int)
-				switch code {
-				case 100008:
-					return ErrInvalidPayment
-				case 100056:
-					return ErrNeedEmailAuthorize
-				}
-
-				if msg, ok := respData["message"].(string); ok {
-					if msg == "Invalid payment" {
-						return ErrInvalidPayment
-					}
-				}
-			}
-
-			return errors.New("BadRequest")
-		}
-
-		return errors.New("BadRequest noBody")
-	}
-
-	if !(resp.StatusCode >= 200 && resp.StatusCode < 300) {
-		return errors.New(resp.Request.URL.String() + " status is not ok: " + string(respBody))
-	}
-
-	return nil
-}
-
 func (ba *BaseAction) log(lvl zapcore.Level, msg string, fields ...zap.Field) {
 	if ba.logger == nil {
 		return
@@ -0,0 +1,125 @@
+package httpclient
+
+import (
+	"bufio"
+	"compress/gzip"
+	"compress/zlib"
+	"errors"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// DefaultCompressionEncodings is negotiated via Accept-Encoding when a
+// caller does not provide its own list to DecompressingRoundTripper.
+var DefaultCompressionEncodings = []string{"gzip", "deflate"}
+
+// DecompressingRoundTripper negotiates Content-Encoding with the server
+// and transparently decodes compressed response bodies, following the
+// pattern used by clients like goreq.
+type DecompressingRoundTripper struct {
+	Proxied   http.RoundTripper
+	Encodings []string
+}
+
+func (drt *DecompressingRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Header.Get("Accept-Encoding") == "" {
+		encodings := drt.Encodings
+		if len(encodings) == 0 {
+			encodings = DefaultCompressionEncodings
+		}
+		req.Header.Set("Accept-Encoding", strings.Join(encodings, ", "))
+	}
+
+	res, err := drt.Proxied.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := decodeBody(res.Header.Get("Content-Encoding"), res.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if body != nil {
+		res.Body = body
+		res.Header.Del("Content-Encoding")
+		res.Header.Del("Content-Length")
+		res.ContentLength = -1
+	}
+
+	return res, nil
+}
+
+// decodeBody returns nil, nil when encoding is not one we decode, or
+// when the body is empty, so the caller leaves the response body
+// untouched. An empty body with a Content-Encoding set is normal for
+// 204/304 and HEAD responses (and for some CDNs/proxies that echo the
+// negotiated encoding regardless of body presence), and must not be fed
+// to gzip.NewReader/zlib.NewReader, which fail on EOF with no data.
+func decodeBody(encoding string, body io.ReadCloser) (io.ReadCloser, error) {
+	newDecompressor := decompressorFor(strings.ToLower(strings.TrimSpace(encoding)))
+	if newDecompressor == nil {
+		return nil, nil
+	}
+
+	br := bufio.NewReader(body)
+	if _, err := br.Peek(1); err != nil {
+		if err == io.EOF {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	reader, err := newDecompressor(br)
+	if err != nil {
+		return nil, err
+	}
+
+	return &decompressedBody{reader: reader, wire: body}, nil
+}
+
+func decompressorFor(encoding string) func(io.Reader) (io.ReadCloser, error) {
+	switch encoding {
+	case "gzip":
+		return func(r io.Reader) (io.ReadCloser, error) { return gzip.NewReader(r) }
+	case "deflate", "zlib":
+		return zlib.NewReader
+	default:
+		return nil
+	}
+}
+
+// decompressedBody closes both the decompressor and the original wire
+// body so the underlying connection is released back to the pool.
+type decompressedBody struct {
+	reader io.ReadCloser
+	wire   io.ReadCloser
+}
+
+func (d *decompressedBody) Read(p []byte) (int, error) {
+	return d.reader.Read(p)
+}
+
+func (d *decompressedBody) Close() error {
+	err := d.reader.Close()
+	if wireErr := d.wire.Close(); err == nil {
+		err = wireErr
+	}
+
+	return err
+}
+
+// implement of httption.ProxiedTransport
+func (drt *DecompressingRoundTripper) SetProxy(proxyURL *url.URL) error {
+	switch t := drt.Proxied.(type) {
+	case *http.Transport:
+		t.Proxy = http.ProxyURL(proxyURL)
+	default:
+		return errors.New("DecompressingRoundTripper.SetProxy() cannot set proxy because transport has unknown type")
+	}
+
+	return nil
+}
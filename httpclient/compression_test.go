@@ -0,0 +1,153 @@
+package httpclient
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestDecompressingRoundTripper_Gzip(t *testing.T) {
+	const want = `{"hello":"world"}`
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Encoding", "gzip")
+		gz := gzip.NewWriter(w)
+		gz.Write([]byte(want))
+		gz.Close()
+	}))
+	defer srv.Close()
+
+	client := &http.Client{Transport: &DecompressingRoundTripper{Proxied: http.DefaultTransport}}
+
+	resp, err := client.Get(srv.URL)
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("ReadAll: %v", err)
+	}
+
+	if string(body) != want {
+		t.Errorf("body = %q, want %q", body, want)
+	}
+	if resp.Header.Get("Content-Encoding") != "" {
+		t.Error("expected Content-Encoding to be stripped once decoded")
+	}
+}
+
+func TestDecompressingRoundTripper_Deflate(t *testing.T) {
+	const want = `{"hello":"deflate"}`
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Encoding", "deflate")
+		zw := zlib.NewWriter(w)
+		zw.Write([]byte(want))
+		zw.Close()
+	}))
+	defer srv.Close()
+
+	client := &http.Client{Transport: &DecompressingRoundTripper{Proxied: http.DefaultTransport}}
+
+	resp, err := client.Get(srv.URL)
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("ReadAll: %v", err)
+	}
+
+	if string(body) != want {
+		t.Errorf("body = %q, want %q", body, want)
+	}
+}
+
+func TestDecompressingRoundTripper_UnrecognizedEncodingPassesThrough(t *testing.T) {
+	const want = "raw body, not actually brotli"
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Encoding", "br")
+		io.WriteString(w, want)
+	}))
+	defer srv.Close()
+
+	client := &http.Client{Transport: &DecompressingRoundTripper{Proxied: http.DefaultTransport}}
+
+	resp, err := client.Get(srv.URL)
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("ReadAll: %v", err)
+	}
+
+	if string(body) != want {
+		t.Errorf("body = %q, want %q", body, want)
+	}
+	if resp.Header.Get("Content-Encoding") != "br" {
+		t.Error("expected an encoding we don't decode to be left untouched")
+	}
+}
+
+func TestDecompressingRoundTripper_EmptyBodyWithContentEncoding(t *testing.T) {
+	statuses := []int{http.StatusNoContent, http.StatusNotModified}
+
+	for _, status := range statuses {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Encoding", "gzip")
+			w.WriteHeader(status)
+		}))
+
+		client := &http.Client{Transport: &DecompressingRoundTripper{Proxied: http.DefaultTransport}}
+
+		resp, err := client.Get(srv.URL)
+		if err != nil {
+			srv.Close()
+			t.Fatalf("Get (status %d): %v", status, err)
+		}
+
+		body, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			srv.Close()
+			t.Fatalf("ReadAll (status %d): %v", status, err)
+		}
+		if len(body) != 0 {
+			t.Errorf("status %d: body = %q, want empty", status, body)
+		}
+
+		srv.Close()
+	}
+}
+
+func TestDecodeBody_EmptyReaderPassesThrough(t *testing.T) {
+	body, err := decodeBody("gzip", io.NopCloser(bytes.NewReader(nil)))
+	if err != nil {
+		t.Fatalf("decodeBody: %v", err)
+	}
+	if body != nil {
+		t.Error("expected an empty body to pass through as nil, nil")
+	}
+}
+
+func TestDecodeBody_UnknownEncoding(t *testing.T) {
+	body, err := decodeBody("br", io.NopCloser(bytes.NewReader([]byte("data"))))
+	if err != nil {
+		t.Fatalf("decodeBody: %v", err)
+	}
+	if body != nil {
+		t.Error("expected an unrecognized encoding to pass through as nil, nil")
+	}
+}
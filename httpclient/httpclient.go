@@ -22,7 +22,7 @@ func NewClient(logger *zap.Logger, proxyUrl *url.URL) *http.Client {
 	}
 
 	if logger != nil {
-		transport = &LoggingRoundTripper{transport, logger}
+		transport = &LoggingRoundTripper{Proxied: transport, Logger: NewZapLogger(logger)}
 	}
 
 	//transport.TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
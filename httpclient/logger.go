@@ -0,0 +1,41 @@
+package httpclient
+
+import "go.uber.org/zap"
+
+// Logger is the minimal structured-logging interface LoggingRoundTripper
+// needs. It mirrors the (msg string, keysAndValues ...any) shape shared
+// by slog and logr, so callers not using zap can plug in slog, logrus,
+// or anything else via a small adapter like zapLogger below.
+type Logger interface {
+	Debug(msg string, keysAndValues ...any)
+	Error(msg string, keysAndValues ...any)
+}
+
+type zapLogger struct {
+	l *zap.Logger
+}
+
+// NewZapLogger adapts a *zap.Logger to Logger. It is the default used
+// by NewClient.
+func NewZapLogger(l *zap.Logger) Logger {
+	return &zapLogger{l: l}
+}
+
+func (z *zapLogger) Debug(msg string, keysAndValues ...any) {
+	z.l.Debug(msg, toZapFields(keysAndValues)...)
+}
+
+func (z *zapLogger) Error(msg string, keysAndValues ...any) {
+	z.l.Error(msg, toZapFields(keysAndValues)...)
+}
+
+func toZapFields(keysAndValues []any) []zap.Field {
+	fields := make([]zap.Field, 0, len(keysAndValues)/2)
+
+	for i := 0; i+1 < len(keysAndValues); i += 2 {
+		key, _ := keysAndValues[i].(string)
+		fields = append(fields, zap.Any(key, keysAndValues[i+1]))
+	}
+
+	return fields
+}
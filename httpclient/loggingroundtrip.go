@@ -3,56 +3,95 @@ package httpclient
 import (
 	"bytes"
 	"errors"
+	"fmt"
 	"io"
 	"net/http"
-	"net/http/httputil"
 	"net/url"
-
-	"go.uber.org/zap"
+	"strings"
+	"time"
 )
 
+// DefaultLogRedactHeaders lists the headers LoggingRoundTripper redacts
+// when LogConfig.RedactHeaders is unset.
+var DefaultLogRedactHeaders = []string{"Authorization", "Cookie", "Set-Cookie", "Proxy-Authorization"}
+
+// LogConfig controls how LoggingRoundTripper renders request/response
+// bodies and which headers it redacts.
+type LogConfig struct {
+	// RedactHeaders replaces these header values with "[REDACTED]"
+	// before logging. Defaults to DefaultLogRedactHeaders when empty.
+	RedactHeaders []string
+
+	// BodyLimit truncates logged bodies past this many bytes. 0 means
+	// no limit.
+	BodyLimit int
+
+	// BodyContentTypes, when non-empty, only logs bodies whose
+	// Content-Type starts with one of these prefixes; others are
+	// logged as "<N bytes elided>".
+	BodyContentTypes []string
+}
+
+// RequestLog is the structured, loggable view of an outgoing request.
+type RequestLog struct {
+	Method  string
+	URL     string
+	Headers http.Header
+	Body    string
+}
+
+// ResponseLog is the structured, loggable view of a received response.
+type ResponseLog struct {
+	Status   string
+	Headers  http.Header
+	Body     string
+	Duration time.Duration
+}
+
 // This type implements the http.RoundTripper interface
 type LoggingRoundTripper struct {
 	Proxied http.RoundTripper
-	logger  *zap.Logger
+	Logger  Logger
+	Config  LogConfig
 }
 
-func (lrt LoggingRoundTripper) RoundTrip(req *http.Request) (res *http.Response, err error) {
-	if lrt.logger != nil {
-		reqOutBytes, err := httputil.DumpRequest(req, true)
-		if err != nil {
-			return nil, err
-		}
-		// Do "before sending requests" actions here.
-		lrt.logger.Debug("HttpClient Sending request",
-			zap.ByteString("request", reqOutBytes),
-		)
+func (lrt *LoggingRoundTripper) RoundTrip(req *http.Request) (res *http.Response, err error) {
+	if lrt.Logger == nil {
+		return lrt.Proxied.RoundTrip(req)
+	}
+
+	reqBody, err := safeInspectContent(&req.Body)
+	if err != nil {
+		return nil, err
 	}
 
-	// Send the request, get the response (or the error)
+	// Do "before sending requests" actions here.
+	lrt.Logger.Debug("HttpClient sending request", logFields(lrt.requestLog(req, reqBody))...)
+
+	start := time.Now()
 	res, err = lrt.Proxied.RoundTrip(req)
+	duration := time.Since(start)
 
-	if lrt.logger != nil {
-		// Handle the result.
+	if err != nil {
+		lrt.Logger.Error("HttpClient RoundTrip error", "error", err)
+		return nil, err
+	}
+
+	var resBody []byte
+	if bodyAllowedForStatus(res.StatusCode) {
+		resBody, err = safeInspectContent(&res.Body)
 		if err != nil {
-			lrt.logger.Error("SolverClient RoundTrip error", zap.Error(err))
-		} else {
-			respBytes, err := httputil.DumpResponse(res, true)
-			if err != nil {
-				return nil, err
-			}
-
-			lrt.logger.Debug("HttpClient Received response",
-				zap.ByteString("response", respBytes),
-			)
+			return nil, err
 		}
 	}
 
-	return
+	lrt.Logger.Debug("HttpClient received response", logFields(lrt.responseLog(res, resBody, duration))...)
+
+	return res, nil
 }
 
 // implement of httption.ProxiedTransport
-func (lrt LoggingRoundTripper) SetProxy(proxyURL *url.URL) error {
+func (lrt *LoggingRoundTripper) SetProxy(proxyURL *url.URL) error {
 	switch t := lrt.Proxied.(type) {
 	case *http.Transport:
 		t.Proxy = http.ProxyURL(proxyURL)
@@ -63,6 +102,79 @@ func (lrt LoggingRoundTripper) SetProxy(proxyURL *url.URL) error {
 	return nil
 }
 
+func (lrt *LoggingRoundTripper) requestLog(req *http.Request, body []byte) RequestLog {
+	return RequestLog{
+		Method:  req.Method,
+		URL:     req.URL.String(),
+		Headers: lrt.redactedHeaders(req.Header),
+		Body:    lrt.renderBody(req.Header.Get("Content-Type"), body),
+	}
+}
+
+func (lrt *LoggingRoundTripper) responseLog(res *http.Response, body []byte, d time.Duration) ResponseLog {
+	return ResponseLog{
+		Status:   res.Status,
+		Headers:  lrt.redactedHeaders(res.Header),
+		Body:     lrt.renderBody(res.Header.Get("Content-Type"), body),
+		Duration: d,
+	}
+}
+
+func (lrt *LoggingRoundTripper) redactedHeaders(h http.Header) http.Header {
+	names := lrt.Config.RedactHeaders
+	if len(names) == 0 {
+		names = DefaultLogRedactHeaders
+	}
+
+	redacted := h.Clone()
+	for _, name := range names {
+		if redacted.Get(name) != "" {
+			redacted.Set(name, "[REDACTED]")
+		}
+	}
+
+	return redacted
+}
+
+func (lrt *LoggingRoundTripper) renderBody(contentType string, body []byte) string {
+	if len(body) == 0 {
+		return ""
+	}
+
+	if types := lrt.Config.BodyContentTypes; len(types) > 0 && !hasAnyPrefix(contentType, types) {
+		return fmt.Sprintf("<%d bytes elided>", len(body))
+	}
+
+	if limit := lrt.Config.BodyLimit; limit > 0 && len(body) > limit {
+		return fmt.Sprintf("%s...<%d more bytes>", body[:limit], len(body)-limit)
+	}
+
+	return string(body)
+}
+
+func hasAnyPrefix(s string, prefixes []string) bool {
+	for _, p := range prefixes {
+		if strings.HasPrefix(s, p) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// logFields flattens a RequestLog/ResponseLog into the alternating
+// key/value pairs Logger.Debug and Logger.Error expect.
+func logFields(v any) []any {
+	switch l := v.(type) {
+	case RequestLog:
+		return []any{"method", l.Method, "url", l.URL, "headers", l.Headers, "body", l.Body}
+	case ResponseLog:
+		return []any{"status", l.Status, "headers", l.Headers, "body", l.Body, "duration", l.Duration}
+	default:
+		return nil
+	}
+}
+
 // bodyAllowedForStatus reports whether a given response status code
 // permits a body. See RFC 7230, section 3.3.
 func bodyAllowedForStatus(status int) bool {
@@ -77,6 +189,16 @@ func bodyAllowedForStatus(status int) bool {
 	return true
 }
 
+// safeInspectContent is inspectContent but a no-op (returning nil, nil)
+// when r or *r is nil, so a bodyless request/response doesn't error.
+func safeInspectContent(r *io.ReadCloser) ([]byte, error) {
+	if r == nil || *r == nil {
+		return nil, nil
+	}
+
+	return inspectContent(r)
+}
+
 func inspectContent(r *io.ReadCloser) ([]byte, error) {
 	if r == nil {
 		return nil, errors.New("ReadCloser pointer is nil")
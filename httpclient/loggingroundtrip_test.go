@@ -0,0 +1,151 @@
+package httpclient
+
+import (
+	"net/http"
+	"testing"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestLoggingRoundTripper_RedactedHeaders(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  LogConfig
+		headers http.Header
+		want    string
+	}{
+		{
+			name:    "default redact list hides Authorization",
+			config:  LogConfig{},
+			headers: http.Header{"Authorization": {"Bearer secret"}},
+			want:    "[REDACTED]",
+		},
+		{
+			name:    "default redact list leaves unlisted headers alone",
+			config:  LogConfig{},
+			headers: http.Header{"X-Request-Id": {"abc-123"}},
+			want:    "abc-123",
+		},
+		{
+			name:    "custom redact list overrides the default",
+			config:  LogConfig{RedactHeaders: []string{"X-Api-Key"}},
+			headers: http.Header{"X-Api-Key": {"shh"}, "Authorization": {"Bearer secret"}},
+			want:    "shh", // checked per-header below
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lrt := &LoggingRoundTripper{Config: tt.config}
+			got := lrt.redactedHeaders(tt.headers)
+
+			switch tt.name {
+			case "custom redact list overrides the default":
+				if got.Get("X-Api-Key") != "[REDACTED]" {
+					t.Errorf("X-Api-Key = %q, want [REDACTED]", got.Get("X-Api-Key"))
+				}
+				if got.Get("Authorization") != "Bearer secret" {
+					t.Errorf("Authorization = %q, want untouched since it's not in the custom list", got.Get("Authorization"))
+				}
+			default:
+				for name := range tt.headers {
+					if got.Get(name) != tt.want {
+						t.Errorf("%s = %q, want %q", name, got.Get(name), tt.want)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestLoggingRoundTripper_RedactedHeaders_DoesNotMutateOriginal(t *testing.T) {
+	lrt := &LoggingRoundTripper{}
+	original := http.Header{"Authorization": {"Bearer secret"}}
+
+	lrt.redactedHeaders(original)
+
+	if original.Get("Authorization") != "Bearer secret" {
+		t.Error("expected redactedHeaders to operate on a clone, not mutate the input")
+	}
+}
+
+func TestLoggingRoundTripper_RenderBody(t *testing.T) {
+	tests := []struct {
+		name        string
+		config      LogConfig
+		contentType string
+		body        []byte
+		want        string
+	}{
+		{
+			name: "empty body renders empty string",
+			body: nil,
+			want: "",
+		},
+		{
+			name: "no limit or content-type filter renders as-is",
+			body: []byte("hello world"),
+			want: "hello world",
+		},
+		{
+			name:   "body over the limit is truncated with a suffix",
+			config: LogConfig{BodyLimit: 5},
+			body:   []byte("hello world"),
+			want:   "hello...<6 more bytes>",
+		},
+		{
+			name:        "content-type filter allows a matching prefix",
+			config:      LogConfig{BodyContentTypes: []string{"application/json"}},
+			contentType: "application/json; charset=utf-8",
+			body:        []byte(`{"a":1}`),
+			want:        `{"a":1}`,
+		},
+		{
+			name:        "content-type filter elides a non-matching prefix",
+			config:      LogConfig{BodyContentTypes: []string{"application/json"}},
+			contentType: "image/png",
+			body:        []byte{0x89, 0x50, 0x4e, 0x47},
+			want:        "<4 bytes elided>",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lrt := &LoggingRoundTripper{Config: tt.config}
+			if got := lrt.renderBody(tt.contentType, tt.body); got != tt.want {
+				t.Errorf("renderBody() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestZapLogger_Adapter(t *testing.T) {
+	core, logs := observer.New(zap.DebugLevel)
+	logger := NewZapLogger(zap.New(core))
+
+	logger.Debug("sending request", "method", "GET", "url", "http://example.com")
+	logger.Error("round trip error", "error", "boom")
+
+	entries := logs.All()
+	if len(entries) != 2 {
+		t.Fatalf("got %d log entries, want 2", len(entries))
+	}
+
+	debugEntry := entries[0]
+	if debugEntry.Message != "sending request" {
+		t.Errorf("message = %q, want %q", debugEntry.Message, "sending request")
+	}
+	fields := debugEntry.ContextMap()
+	if fields["method"] != "GET" || fields["url"] != "http://example.com" {
+		t.Errorf("fields = %v, want method=GET url=http://example.com", fields)
+	}
+
+	errorEntry := entries[1]
+	if errorEntry.Message != "round trip error" {
+		t.Errorf("message = %q, want %q", errorEntry.Message, "round trip error")
+	}
+	if errorEntry.ContextMap()["error"] != "boom" {
+		t.Errorf("error field = %v, want boom", errorEntry.ContextMap()["error"])
+	}
+}
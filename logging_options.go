@@ -0,0 +1,72 @@
+package httption
+
+import (
+	"errors"
+
+	"github.com/sik0-o/httption/httpclient"
+)
+
+// WithLogRedactHeaders configures which request/response headers the
+// action's httpclient.LoggingRoundTripper replaces with "[REDACTED]".
+// Defaults to Authorization, Cookie, Set-Cookie, Proxy-Authorization.
+func WithLogRedactHeaders(names ...string) Option {
+	return func(ba *BaseAction) error {
+		lrt, ok := ba.loggingRoundTripper()
+		if !ok {
+			return errLoggingNotConfigured
+		}
+
+		lrt.Config.RedactHeaders = names
+
+		return nil
+	}
+}
+
+// WithLogBodyLimit truncates logged request/response bodies past n
+// bytes. n <= 0 means no limit.
+func WithLogBodyLimit(n int) Option {
+	return func(ba *BaseAction) error {
+		lrt, ok := ba.loggingRoundTripper()
+		if !ok {
+			return errLoggingNotConfigured
+		}
+
+		lrt.Config.BodyLimit = n
+
+		return nil
+	}
+}
+
+// WithLogBodyContentTypes restricts logged bodies to those whose
+// Content-Type starts with one of the given prefixes; others are
+// logged as "<N bytes elided>" so binary payloads don't flood logs.
+func WithLogBodyContentTypes(prefixes ...string) Option {
+	return func(ba *BaseAction) error {
+		lrt, ok := ba.loggingRoundTripper()
+		if !ok {
+			return errLoggingNotConfigured
+		}
+
+		lrt.Config.BodyContentTypes = prefixes
+
+		return nil
+	}
+}
+
+var errLoggingNotConfigured = errors.New("BaseAction transport has no httpclient.LoggingRoundTripper to configure; build the client via httpclient.NewClient with a logger")
+
+// loggingRoundTripper finds the *httpclient.LoggingRoundTripper
+// installed on the action's transport, accounting for it being wrapped
+// around a DecompressingRoundTripper by WithCompression.
+func (ba *BaseAction) loggingRoundTripper() (*httpclient.LoggingRoundTripper, bool) {
+	switch t := ba.client.Transport.(type) {
+	case *httpclient.LoggingRoundTripper:
+		return t, true
+	case *httpclient.DecompressingRoundTripper:
+		if lrt, ok := t.Proxied.(*httpclient.LoggingRoundTripper); ok {
+			return lrt, true
+		}
+	}
+
+	return nil, false
+}
@@ -0,0 +1,87 @@
+package httption
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/sik0-o/httption/httpclient"
+)
+
+func TestBaseAction_LoggingRoundTripper(t *testing.T) {
+	t.Run("not configured without a LoggingRoundTripper", func(t *testing.T) {
+		ba := NewBaseAction(newTestClient(nil), "GET", "http://example.com")
+
+		if _, ok := ba.loggingRoundTripper(); ok {
+			t.Error("expected no LoggingRoundTripper to be found")
+		}
+	})
+
+	t.Run("found directly on the transport", func(t *testing.T) {
+		lrt := &httpclient.LoggingRoundTripper{}
+		ba := NewBaseAction(newTestClient(lrt), "GET", "http://example.com")
+
+		got, ok := ba.loggingRoundTripper()
+		if !ok || got != lrt {
+			t.Error("expected to find the LoggingRoundTripper set directly on the transport")
+		}
+	})
+
+	t.Run("found wrapped by a DecompressingRoundTripper", func(t *testing.T) {
+		lrt := &httpclient.LoggingRoundTripper{}
+		wrapped := &httpclient.DecompressingRoundTripper{Proxied: lrt}
+		ba := NewBaseAction(newTestClient(wrapped), "GET", "http://example.com")
+
+		got, ok := ba.loggingRoundTripper()
+		if !ok || got != lrt {
+			t.Error("expected to find the LoggingRoundTripper wrapped by a DecompressingRoundTripper")
+		}
+	})
+}
+
+func TestWithLogRedactHeaders(t *testing.T) {
+	lrt := &httpclient.LoggingRoundTripper{}
+	ba := NewBaseAction(newTestClient(lrt), "GET", "http://example.com")
+
+	if err := WithLogRedactHeaders("X-Api-Key")(ba); err != nil {
+		t.Fatalf("WithLogRedactHeaders: %v", err)
+	}
+	if len(lrt.Config.RedactHeaders) != 1 || lrt.Config.RedactHeaders[0] != "X-Api-Key" {
+		t.Errorf("RedactHeaders = %v, want [X-Api-Key]", lrt.Config.RedactHeaders)
+	}
+}
+
+func TestWithLogBodyLimit(t *testing.T) {
+	lrt := &httpclient.LoggingRoundTripper{}
+	ba := NewBaseAction(newTestClient(lrt), "GET", "http://example.com")
+
+	if err := WithLogBodyLimit(64)(ba); err != nil {
+		t.Fatalf("WithLogBodyLimit: %v", err)
+	}
+	if lrt.Config.BodyLimit != 64 {
+		t.Errorf("BodyLimit = %d, want 64", lrt.Config.BodyLimit)
+	}
+}
+
+func TestWithLogBodyContentTypes(t *testing.T) {
+	lrt := &httpclient.LoggingRoundTripper{}
+	ba := NewBaseAction(newTestClient(lrt), "GET", "http://example.com")
+
+	if err := WithLogBodyContentTypes("application/json")(ba); err != nil {
+		t.Fatalf("WithLogBodyContentTypes: %v", err)
+	}
+	if len(lrt.Config.BodyContentTypes) != 1 || lrt.Config.BodyContentTypes[0] != "application/json" {
+		t.Errorf("BodyContentTypes = %v, want [application/json]", lrt.Config.BodyContentTypes)
+	}
+}
+
+func TestWithLogRedactHeaders_ErrorsWithoutLoggingRoundTripper(t *testing.T) {
+	ba := NewBaseAction(newTestClient(nil), "GET", "http://example.com")
+
+	if err := WithLogRedactHeaders("X-Api-Key")(ba); err != errLoggingNotConfigured {
+		t.Errorf("err = %v, want errLoggingNotConfigured", err)
+	}
+}
+
+func newTestClient(transport http.RoundTripper) *http.Client {
+	return &http.Client{Transport: transport}
+}
@@ -0,0 +1,116 @@
+package httption
+
+import (
+	"context"
+	"net/http"
+)
+
+// Handler performs a single HTTP round trip within the middleware
+// chain. The innermost Handler built by BaseAction simply delegates to
+// its *http.Client.
+type Handler func(ctx context.Context, req *http.Request) (*http.Response, error)
+
+// Middleware wraps a Handler to add cross-cutting behavior around the
+// request/response cycle, e.g. metrics, tracing span injection,
+// signature verification, or rate-limit backoff, without forking
+// BaseAction itself.
+type Middleware func(next Handler) Handler
+
+// BeforeRequestFunc inspects or mutates a request immediately before it
+// is handed to the middleware chain.
+type BeforeRequestFunc func(req *http.Request) error
+
+// AfterResponseFunc inspects a response and its fully-read body once
+// the middleware chain returns.
+type AfterResponseFunc func(resp *http.Response, body []byte) error
+
+// RetryFunc is invoked before each retry delay; returning skip true
+// aborts the retry loop without making a further attempt.
+type RetryFunc func(attempt uint, lastErr error) (skip bool)
+
+// WithMiddleware appends to the chain invoked around every request.
+// Middleware registered first wraps outermost, so it sees the request
+// before and the response after all later-registered middleware.
+func WithMiddleware(mw ...Middleware) Option {
+	return func(ba *BaseAction) error {
+		ba.middleware = append(ba.middleware, mw...)
+		return nil
+	}
+}
+
+// WithBeforeRequest registers a hook run just before the request is
+// sent, in registration order.
+func WithBeforeRequest(fn BeforeRequestFunc) Option {
+	return func(ba *BaseAction) error {
+		ba.beforeRequest = append(ba.beforeRequest, fn)
+		return nil
+	}
+}
+
+// WithAfterResponse registers a hook run once the response body has
+// been read, in registration order.
+func WithAfterResponse(fn AfterResponseFunc) Option {
+	return func(ba *BaseAction) error {
+		ba.afterResponse = append(ba.afterResponse, fn)
+		return nil
+	}
+}
+
+// WithOnRetry registers a hook run before each retry delay.
+func WithOnRetry(fn RetryFunc) Option {
+	return func(ba *BaseAction) error {
+		ba.onRetry = append(ba.onRetry, fn)
+		return nil
+	}
+}
+
+// buildHandler wraps the action's base Handler (a plain client.Do) with
+// the registered middleware, outermost first. It is built once per
+// Setup call and reused across retries.
+func (ba *BaseAction) buildHandler() Handler {
+	h := Handler(func(_ context.Context, req *http.Request) (*http.Response, error) {
+		return ba.client.Do(req)
+	})
+
+	for i := len(ba.middleware) - 1; i >= 0; i-- {
+		h = ba.middleware[i](h)
+	}
+
+	return h
+}
+
+// runBeforeRequest runs the registered BeforeRequestFunc hooks in order,
+// stopping at the first error.
+func (ba *BaseAction) runBeforeRequest(req *http.Request) error {
+	for _, fn := range ba.beforeRequest {
+		if err := fn(req); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// runAfterResponse runs the registered AfterResponseFunc hooks in
+// order, stopping at the first error.
+func (ba *BaseAction) runAfterResponse(resp *http.Response, body []byte) error {
+	for _, fn := range ba.afterResponse {
+		if err := fn(resp, body); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// runOnRetry runs the registered RetryFunc hooks before a retry delay;
+// it reports skip true if any hook asks to abort the retry loop.
+func (ba *BaseAction) runOnRetry(attempt uint, lastErr error) (skip bool) {
+	for _, fn := range ba.onRetry {
+		if fn(attempt, lastErr) {
+			return true
+		}
+	}
+
+	return false
+}
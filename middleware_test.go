@@ -0,0 +1,138 @@
+package httption
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+)
+
+func TestBuildHandler_Ordering(t *testing.T) {
+	ba := NewBaseAction(&http.Client{}, "GET", "http://example.com")
+
+	var order []string
+	wrap := func(name string) Middleware {
+		return func(next Handler) Handler {
+			return func(ctx context.Context, req *http.Request) (*http.Response, error) {
+				order = append(order, name)
+				return next(ctx, req)
+			}
+		}
+	}
+
+	ba.middleware = []Middleware{wrap("outer"), wrap("inner")}
+	ba.client = &http.Client{Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		order = append(order, "base")
+		return &http.Response{StatusCode: 200, Body: http.NoBody}, nil
+	})}
+
+	handler := ba.buildHandler()
+
+	req, _ := http.NewRequest("GET", "http://example.com", nil)
+	if _, err := handler(context.Background(), req); err != nil {
+		t.Fatalf("handler: %v", err)
+	}
+
+	want := []string{"outer", "inner", "base"}
+	if len(order) != len(want) {
+		t.Fatalf("order = %v, want %v", order, want)
+	}
+	for i := range want {
+		if order[i] != want[i] {
+			t.Errorf("order[%d] = %q, want %q", i, order[i], want[i])
+		}
+	}
+}
+
+func TestBuildHandler_NoMiddlewareCallsClientDirectly(t *testing.T) {
+	called := false
+	ba := NewBaseAction(&http.Client{Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		called = true
+		return &http.Response{StatusCode: 200, Body: http.NoBody}, nil
+	})}, "GET", "http://example.com")
+
+	req, _ := http.NewRequest("GET", "http://example.com", nil)
+	if _, err := ba.buildHandler()(context.Background(), req); err != nil {
+		t.Fatalf("handler: %v", err)
+	}
+	if !called {
+		t.Error("expected the base handler to reach the client's transport")
+	}
+}
+
+func TestRunBeforeRequest_StopsAtFirstError(t *testing.T) {
+	ba := NewBaseAction(&http.Client{}, "GET", "http://example.com")
+
+	var calls []string
+	boom := errors.New("boom")
+
+	ba.beforeRequest = []BeforeRequestFunc{
+		func(req *http.Request) error { calls = append(calls, "first"); return nil },
+		func(req *http.Request) error { calls = append(calls, "second"); return boom },
+		func(req *http.Request) error { calls = append(calls, "third"); return nil },
+	}
+
+	req, _ := http.NewRequest("GET", "http://example.com", nil)
+	if err := ba.runBeforeRequest(req); !errors.Is(err, boom) {
+		t.Errorf("err = %v, want boom", err)
+	}
+	if len(calls) != 2 {
+		t.Errorf("calls = %v, want hooks after the error to be skipped", calls)
+	}
+}
+
+func TestRunAfterResponse_StopsAtFirstError(t *testing.T) {
+	ba := NewBaseAction(&http.Client{}, "GET", "http://example.com")
+
+	var calls []string
+	boom := errors.New("boom")
+
+	ba.afterResponse = []AfterResponseFunc{
+		func(resp *http.Response, body []byte) error { calls = append(calls, "first"); return boom },
+		func(resp *http.Response, body []byte) error { calls = append(calls, "second"); return nil },
+	}
+
+	resp := &http.Response{StatusCode: 200}
+	if err := ba.runAfterResponse(resp, nil); !errors.Is(err, boom) {
+		t.Errorf("err = %v, want boom", err)
+	}
+	if len(calls) != 1 {
+		t.Errorf("calls = %v, want only the failing hook to run", calls)
+	}
+}
+
+func TestRunOnRetry_SkipsOnFirstTrueAndShortCircuits(t *testing.T) {
+	ba := NewBaseAction(&http.Client{}, "GET", "http://example.com")
+
+	var calls []string
+	ba.onRetry = []RetryFunc{
+		func(attempt uint, lastErr error) bool { calls = append(calls, "first"); return true },
+		func(attempt uint, lastErr error) bool { calls = append(calls, "second"); return false },
+	}
+
+	if skip := ba.runOnRetry(1, errors.New("x")); !skip {
+		t.Error("expected runOnRetry to report skip=true")
+	}
+	if len(calls) != 1 {
+		t.Errorf("calls = %v, want the loop to stop once a hook returns true", calls)
+	}
+}
+
+func TestRunOnRetry_FalseWhenNoHookSkips(t *testing.T) {
+	ba := NewBaseAction(&http.Client{}, "GET", "http://example.com")
+
+	ba.onRetry = []RetryFunc{
+		func(attempt uint, lastErr error) bool { return false },
+		func(attempt uint, lastErr error) bool { return false },
+	}
+
+	if skip := ba.runOnRetry(1, errors.New("x")); skip {
+		t.Error("expected runOnRetry to report skip=false when no hook asks to abort")
+	}
+}
+
+type roundTripFunc func(req *http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
@@ -99,6 +99,18 @@ func WithRetryDelay(retryDelay time.Duration) Option {
 	}
 }
 
+// WithAuth registers an Authenticator that is applied after headers and
+// body are set up. Multiple calls compose: authenticators run in the
+// order they were added, so e.g. a header token and a query-string
+// account id can both be attached to the same request.
+func WithAuth(a Authenticator) Option {
+	return func(ba *BaseAction) error {
+		ba.authenticators = append(ba.authenticators, a)
+
+		return nil
+	}
+}
+
 type StatusCodeHandlerFunc func(client *http.Client) bool
 
 func WithStatusCodeHandler(code int, h StatusCodeHandlerFunc) Option {
@@ -0,0 +1,136 @@
+package httption
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// BackoffStrategy computes the delay before a retry attempt (1-based).
+type BackoffStrategy func(attempt uint) time.Duration
+
+// ConstantBackoff retries after the same delay every time.
+func ConstantBackoff(delay time.Duration) BackoffStrategy {
+	return func(uint) time.Duration {
+		return delay
+	}
+}
+
+// LinearBackoff grows the delay linearly with the attempt number.
+func LinearBackoff(step time.Duration) BackoffStrategy {
+	return func(attempt uint) time.Duration {
+		return step * time.Duration(attempt)
+	}
+}
+
+// ExponentialBackoff doubles the delay every attempt starting from
+// base, capped at max, and applies full jitter (a uniformly random
+// delay between 0 and the computed cap) to avoid synchronized retries.
+func ExponentialBackoff(base, max time.Duration) BackoffStrategy {
+	return func(attempt uint) time.Duration {
+		delay := base
+		for i := uint(1); i < attempt && delay < max; i++ {
+			delay *= 2
+		}
+		if delay > max {
+			delay = max
+		}
+		if delay <= 0 {
+			return 0
+		}
+
+		return time.Duration(rand.Int63n(int64(delay)))
+	}
+}
+
+// WithTimeout bounds a single attempt, including the time spent waiting
+// on the response.
+func WithTimeout(perAttempt time.Duration) Option {
+	return func(ba *BaseAction) error {
+		ba.perAttemptTimeout = perAttempt
+		return nil
+	}
+}
+
+// WithTotalTimeout bounds the whole Do/DoContext call across all
+// retries.
+func WithTotalTimeout(overall time.Duration) Option {
+	return func(ba *BaseAction) error {
+		ba.totalTimeout = overall
+		return nil
+	}
+}
+
+// WithRetryBackoff overrides the delay between retries with strategy.
+// It takes precedence over WithRetryDelay, but a Retry-After header on
+// a 429/503 response always wins for that attempt.
+func WithRetryBackoff(strategy BackoffStrategy) Option {
+	return func(ba *BaseAction) error {
+		ba.backoff = strategy
+		return nil
+	}
+}
+
+// nextRetryDelay picks the delay before the given retry attempt: a
+// Retry-After header from the previous response takes precedence, then
+// a configured BackoffStrategy, falling back to the static retryDelay.
+func (ba *BaseAction) nextRetryDelay(attempt uint) time.Duration {
+	if ba.retryAfter > 0 {
+		d := ba.retryAfter
+		ba.retryAfter = 0
+		return d
+	}
+
+	if ba.backoff != nil {
+		return ba.backoff(attempt)
+	}
+
+	return ba.retryDelay
+}
+
+// retryAfterDuration reads a Retry-After header (seconds or HTTP-date)
+// from a 429/503 response, returning 0 if absent or unparsable.
+func retryAfterDuration(resp *http.Response) time.Duration {
+	if resp == nil {
+		return 0
+	}
+	if resp.StatusCode != http.StatusTooManyRequests && resp.StatusCode != http.StatusServiceUnavailable {
+		return 0
+	}
+
+	v := resp.Header.Get("Retry-After")
+	if v == "" {
+		return 0
+	}
+
+	if secs, err := strconv.Atoi(v); err == nil {
+		return time.Duration(secs) * time.Second
+	}
+
+	if t, err := http.ParseTime(v); err == nil {
+		return time.Until(t)
+	}
+
+	return 0
+}
+
+// isRetryableError reports whether a failed attempt should be retried:
+// context cancellation never is, and among 4xx responses only 408
+// (Request Timeout) and 429 (Too Many Requests) are.
+func (ba *BaseAction) isRetryableError(err error) bool {
+	if errors.Is(err, context.Canceled) {
+		return false
+	}
+
+	if ba.response != nil {
+		sc := ba.response.StatusCode
+		if sc >= 400 && sc < 500 {
+			return sc == http.StatusRequestTimeout || sc == http.StatusTooManyRequests
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,204 @@
+package httption
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestConstantBackoff(t *testing.T) {
+	backoff := ConstantBackoff(200 * time.Millisecond)
+
+	for _, attempt := range []uint{1, 2, 5} {
+		if got := backoff(attempt); got != 200*time.Millisecond {
+			t.Errorf("ConstantBackoff()(%d) = %v, want 200ms", attempt, got)
+		}
+	}
+}
+
+func TestLinearBackoff(t *testing.T) {
+	backoff := LinearBackoff(100 * time.Millisecond)
+
+	tests := []struct {
+		attempt uint
+		want    time.Duration
+	}{
+		{1, 100 * time.Millisecond},
+		{2, 200 * time.Millisecond},
+		{4, 400 * time.Millisecond},
+	}
+
+	for _, tt := range tests {
+		if got := backoff(tt.attempt); got != tt.want {
+			t.Errorf("LinearBackoff()(%d) = %v, want %v", tt.attempt, got, tt.want)
+		}
+	}
+}
+
+func TestExponentialBackoff_Bounds(t *testing.T) {
+	base := 10 * time.Millisecond
+	max := 160 * time.Millisecond
+	backoff := ExponentialBackoff(base, max)
+
+	for attempt := uint(1); attempt <= 20; attempt++ {
+		for i := 0; i < 20; i++ {
+			got := backoff(attempt)
+			if got < 0 {
+				t.Fatalf("ExponentialBackoff()(%d) = %v, want >= 0", attempt, got)
+			}
+			if got > max {
+				t.Fatalf("ExponentialBackoff()(%d) = %v, want <= max %v", attempt, got, max)
+			}
+		}
+	}
+}
+
+func TestExponentialBackoff_ZeroBase(t *testing.T) {
+	backoff := ExponentialBackoff(0, time.Second)
+
+	if got := backoff(1); got != 0 {
+		t.Errorf("ExponentialBackoff(0, ...)(1) = %v, want 0", got)
+	}
+}
+
+func TestRetryAfterDuration(t *testing.T) {
+	future := time.Now().Add(30 * time.Second)
+
+	tests := []struct {
+		name       string
+		statusCode int
+		header     string
+		wantZero   bool
+		want       time.Duration
+	}{
+		{
+			name:       "no retry-after header",
+			statusCode: http.StatusTooManyRequests,
+			header:     "",
+			wantZero:   true,
+		},
+		{
+			name:       "non-retryable status ignores the header",
+			statusCode: http.StatusOK,
+			header:     "5",
+			wantZero:   true,
+		},
+		{
+			name:       "seconds form on 429",
+			statusCode: http.StatusTooManyRequests,
+			header:     "5",
+			want:       5 * time.Second,
+		},
+		{
+			name:       "seconds form on 503",
+			statusCode: http.StatusServiceUnavailable,
+			header:     "2",
+			want:       2 * time.Second,
+		},
+		{
+			name:       "unparsable header",
+			statusCode: http.StatusTooManyRequests,
+			header:     "not-a-duration",
+			wantZero:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := &http.Response{
+				StatusCode: tt.statusCode,
+				Header:     http.Header{"Retry-After": {tt.header}},
+			}
+			if tt.header == "" {
+				resp.Header = http.Header{}
+			}
+
+			got := retryAfterDuration(resp)
+			if tt.wantZero {
+				if got != 0 {
+					t.Errorf("retryAfterDuration() = %v, want 0", got)
+				}
+				return
+			}
+			if got != tt.want {
+				t.Errorf("retryAfterDuration() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	t.Run("HTTP-date form", func(t *testing.T) {
+		resp := &http.Response{
+			StatusCode: http.StatusTooManyRequests,
+			Header:     http.Header{"Retry-After": {future.UTC().Format(http.TimeFormat)}},
+		}
+
+		got := retryAfterDuration(resp)
+		if got <= 0 || got > 31*time.Second {
+			t.Errorf("retryAfterDuration() = %v, want roughly 30s", got)
+		}
+	})
+
+	t.Run("nil response", func(t *testing.T) {
+		if got := retryAfterDuration(nil); got != 0 {
+			t.Errorf("retryAfterDuration(nil) = %v, want 0", got)
+		}
+	})
+}
+
+func TestBaseAction_NextRetryDelay(t *testing.T) {
+	ba := NewBaseAction(&http.Client{}, "GET", "http://example.com")
+	ba.retryDelay = 50 * time.Millisecond
+
+	if got := ba.nextRetryDelay(1); got != 50*time.Millisecond {
+		t.Errorf("nextRetryDelay() = %v, want static retryDelay 50ms", got)
+	}
+
+	ba.backoff = ConstantBackoff(time.Second)
+	if got := ba.nextRetryDelay(1); got != time.Second {
+		t.Errorf("nextRetryDelay() = %v, want backoff strategy result 1s", got)
+	}
+
+	ba.retryAfter = 3 * time.Second
+	if got := ba.nextRetryDelay(1); got != 3*time.Second {
+		t.Errorf("nextRetryDelay() = %v, want Retry-After to take precedence", got)
+	}
+	if ba.retryAfter != 0 {
+		t.Error("expected retryAfter to be consumed after use")
+	}
+}
+
+func TestBaseAction_IsRetryableError(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		statusCode int
+		want       bool
+	}{
+		{name: "context canceled never retries", err: context.Canceled, want: false},
+		{name: "no response yet retries", err: errors.New("dial error"), want: true},
+		{name: "408 retries", statusCode: http.StatusRequestTimeout, want: true},
+		{name: "429 retries", statusCode: http.StatusTooManyRequests, want: true},
+		{name: "404 does not retry", statusCode: http.StatusNotFound, want: false},
+		{name: "500 retries", statusCode: http.StatusInternalServerError, want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ba := NewBaseAction(&http.Client{}, "GET", "http://example.com")
+			if tt.statusCode != 0 {
+				ba.response = &http.Response{StatusCode: tt.statusCode}
+			}
+
+			err := tt.err
+			if err == nil {
+				err = errors.New("some error")
+			}
+
+			if got := ba.isRetryableError(err); got != tt.want {
+				t.Errorf("isRetryableError() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -1,5 +1,20 @@
 package httption
 
+import "strings"
+
+// headerLookup finds a value in a map[string]string headers collection
+// by a case-insensitive key match, mirroring the case-insensitivity of
+// http.Header.Get for the ba.headers map used before a request is built.
+func headerLookup(headers map[string]string, name string) (string, bool) {
+	for k, v := range headers {
+		if strings.EqualFold(k, name) {
+			return v, true
+		}
+	}
+
+	return "", false
+}
+
 func mergeMaps(source map[string]string, maps ...map[string]string) {
 	for _, mm := range maps {
 		for k, v := range mm {